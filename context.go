@@ -0,0 +1,69 @@
+package routines
+
+import (
+	"context"
+	"time"
+)
+
+// FromContext creates a Routines whose Done() channel closes when ctx is cancelled.
+// It is the inverse of Context() and lets code that already manages a context.Context
+// compose with code built around the Routines interface.
+func FromContext(ctx context.Context) Routines {
+	rv := NewRoutines()
+	r := rv.(*routines)
+	r.srcCtx = ctx
+	r.Go(func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-r.Done():
+		}
+	})
+	return rv
+}
+
+// routinesContext adapts a *routines' Done() channel to the context.Context interface
+// so it can be passed to libraries expecting one.
+type routinesContext struct {
+	r *routines
+}
+
+// Deadline implements context.Context; Routines has no notion of a deadline.
+func (me *routinesContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements context.Context by returning the Routines' own Done() channel.
+func (me *routinesContext) Done() <-chan struct{} {
+	return me.r.Done()
+}
+
+// Err implements context.Context, returning context.Canceled once Stop() has been called.
+func (me *routinesContext) Err() error {
+	select {
+	case <-me.r.Done():
+		return context.Canceled
+	default:
+		return nil
+	}
+}
+
+// Value implements context.Context by delegating to the context.Context that created
+// this Routines via FromContext, if any.
+func (me *routinesContext) Value(key interface{}) interface{} {
+	if me.r.srcCtx != nil {
+		return me.r.srcCtx.Value(key)
+	}
+	return nil
+}
+
+// Context returns a context.Context whose Done() channel is wired to this Routines'
+// own Done() channel and whose Err() returns context.Canceled after Stop() has been
+// called.  Values looked up with Value() are forwarded to the context.Context that
+// created this Routines via FromContext, if any.
+func (me *routines) Context() context.Context {
+	if me == nil {
+		return context.Background()
+	}
+	return &routinesContext{r: me}
+}
@@ -0,0 +1,32 @@
+package routines
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := FromContext(ctx)
+	defer r.Wait()
+
+	cancel()
+	<-r.Done()
+}
+
+func TestRoutines_Context(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	ctx := r.Context()
+	if ctx.Err() != nil {
+		t.Fatalf("expected nil error before Stop(), got %v", ctx.Err())
+	}
+
+	r.Stop()
+	<-ctx.Done()
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("expected context.Canceled after Stop(), got %v", ctx.Err())
+	}
+}
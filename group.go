@@ -0,0 +1,173 @@
+package routines
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nofeaturesonlybugs/errors"
+)
+
+// NamedService pairs a Service with the name it was registered under in a Group.
+type NamedService struct {
+	Name    string
+	Service Service
+}
+
+// GroupOption configures a Group created with NewGroup().
+type GroupOption func(*Group)
+
+// WithGroupName sets the diagnostic name returned by Group.Name().
+func WithGroupName(name string) GroupOption {
+	return func(g *Group) { g.name = name }
+}
+
+// Group manages an ordered collection of Service values.  Start() starts each registered
+// Service in registration order, stopping any that already started - in reverse order -
+// if one fails; Stop() shuts down in reverse order and waits for each.  Group itself
+// implements Service, so groups can nest to form supervision trees analogous to
+// Erlang/OTP, and it eliminates the manual defer-chain of one Stop() per service that
+// composing several services by hand requires.
+type Group struct {
+	name string
+	//
+	mut      sync.Mutex
+	services []NamedService
+	started  []NamedService
+}
+
+// NewGroup creates an empty Group.
+func NewGroup(opts ...GroupOption) *Group {
+	rv := &Group{}
+	for _, opt := range opts {
+		opt(rv)
+	}
+	return rv
+}
+
+// Add registers svc under name and returns the Group so calls can be chained.  Services
+// are started in the order they are added and stopped in the reverse order.
+func (me *Group) Add(name string, svc Service) *Group {
+	if me == nil {
+		return nil
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	me.services = append(me.services, NamedService{Name: name, Service: svc})
+	return me
+}
+
+// Services returns a snapshot of the registered services in registration order.
+func (me *Group) Services() []NamedService {
+	if me == nil {
+		return nil
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	rv := make([]NamedService, len(me.services))
+	copy(rv, me.services)
+	return rv
+}
+
+// Start starts each registered Service in registration order.  If a Service fails to
+// start, the services that already started are stopped in reverse order before the
+// error is returned.
+func (me *Group) Start(routines Routines) error {
+	if me == nil {
+		return errors.NilReceiver().Type(me)
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	for _, ns := range me.services {
+		if err := ns.Service.Start(routines); err != nil {
+			for k := len(me.started) - 1; k >= 0; k-- {
+				me.started[k].Service.Stop()
+			}
+			me.started = nil
+			return fmt.Errorf("routines: group service %q failed to start: %w", ns.Name, err)
+		}
+		me.started = append(me.started, ns)
+	}
+	return nil
+}
+
+// StartContext starts the group using a Routines created from ctx via FromContext.
+func (me *Group) StartContext(ctx context.Context) error {
+	if me == nil {
+		return errors.NilReceiver().Type(me)
+	}
+	return me.Start(FromContext(ctx))
+}
+
+// Stop shuts down every started service in reverse registration order, waiting for each
+// to fully stop before moving on to the next.
+func (me *Group) Stop() {
+	if me == nil {
+		return
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	for k := len(me.started) - 1; k >= 0; k-- {
+		me.started[k].Service.Stop()
+	}
+	me.started = nil
+}
+
+// StopTimeout shuts down every started service in reverse registration order, giving
+// each whatever time remains until the overall deadline d; it returns the first error encountered and
+// leaves the remaining services running so the caller can retry or diagnose them.
+func (me *Group) StopTimeout(d time.Duration) error {
+	if me == nil {
+		return errors.NilReceiver().Type(me)
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	deadline := time.Now().Add(d)
+	for len(me.started) > 0 {
+		k := len(me.started) - 1
+		ns := me.started[k]
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		if err := ns.Service.StopTimeout(remaining); err != nil {
+			return fmt.Errorf("routines: group service %q: %w", ns.Name, err)
+		}
+		me.started = me.started[:k]
+	}
+	return nil
+}
+
+// IsRunning reports whether the group has any started services.
+func (me *Group) IsRunning() bool {
+	if me == nil {
+		return false
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	return len(me.started) > 0
+}
+
+// Name returns the group's diagnostic name, set via WithGroupName(); the zero value is
+// "unnamed".
+func (me *Group) Name() string {
+	if me == nil || me.name == "" {
+		return "unnamed"
+	}
+	return me.name
+}
+
+// Wait blocks until every started service has fully stopped.
+func (me *Group) Wait() {
+	if me == nil {
+		return
+	}
+	me.mut.Lock()
+	started := make([]NamedService, len(me.started))
+	copy(started, me.started)
+	me.mut.Unlock()
+	for _, ns := range started {
+		ns.Service.Wait()
+	}
+}
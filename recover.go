@@ -0,0 +1,107 @@
+package routines
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// initErrors lazily creates the buffered channel backing Errors().
+func (me *routines) initErrors() {
+	me.errorsOnce.Do(func() {
+		me.errorsCh = make(chan error, 8)
+	})
+}
+
+// Errors returns a channel that receives the errors returned or panicked by goroutines
+// started with GoRecover().  The channel is buffered; an error is dropped from it rather
+// than blocking the goroutine that produced it if the buffer is full and nobody is
+// reading from it.  Every error is also recorded losslessly regardless of whether
+// anything reads from this channel; call DrainErrors() to retrieve all of them, for
+// example after Wait() returns.
+func (me *routines) Errors() <-chan error {
+	if me == nil {
+		return nil
+	}
+	me.initErrors()
+	return me.errorsCh
+}
+
+// DrainErrors returns every error returned or panicked by goroutines started with
+// GoRecover() since the last call to DrainErrors(), then clears its internal record of
+// them.
+func (me *routines) DrainErrors() []error {
+	if me == nil {
+		return nil
+	}
+	me.errMut.Lock()
+	defer me.errMut.Unlock()
+	rv := me.errQueue
+	me.errQueue = nil
+	return rv
+}
+
+// sendError records err for DrainErrors(), delivers it on Errors() without blocking, then
+// Stop()s the Routines if it was created with WithFailFast(true).
+func (me *routines) sendError(err error) {
+	me.errMut.Lock()
+	me.errQueue = append(me.errQueue, err)
+	me.errMut.Unlock()
+	me.initErrors()
+	select {
+	case me.errorsCh <- err:
+	default:
+	}
+	if me.failFast {
+		me.Stop()
+	}
+}
+
+// recoverToError invokes fn, recovering any panic and converting it into an error
+// annotated with the stack trace captured by debug.Stack().
+func recoverToError(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in routine: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn()
+}
+
+// GoRecover launches fn as a go routine, recovering any panic and converting it into an
+// error, and delivers a non-nil returned or recovered error on Errors(), as well as
+// recording it for DrainErrors().  If the Routines was created with WithFailFast(true),
+// Stop() is called automatically when such an error arrives.
+//
+// Errors() is an 8-slot buffered channel and drops anything past the 8th unread error;
+// callers that must see every error, for example after Wait() returns, should call
+// DrainErrors() instead of racing to read Errors() as errors arrive.
+func (me *routines) GoRecover(fn func() error) {
+	if me == nil {
+		return
+	}
+	me.Go(func() {
+		if err := recoverToError(fn); err != nil {
+			me.sendError(err)
+		}
+	})
+}
+
+// GoWithRecover launches fn as a fire-and-forget go routine, recovering any panic and
+// passing the recovered value and stack trace to onPanic rather than crashing the
+// program.
+func (me *routines) GoWithRecover(fn func(), onPanic func(recovered interface{}, stack []byte)) {
+	if me == nil {
+		return
+	}
+	me.Go(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				if onPanic != nil {
+					onPanic(r, stack)
+				}
+			}
+		}()
+		fn()
+	})
+}
@@ -0,0 +1,104 @@
+package routines
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoRecover_deliversReturnedError(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	r.GoRecover(func() error {
+		return errors.New("boom")
+	})
+
+	select {
+	case err := <-r.Errors():
+		if err.Error() != "boom" {
+			t.Fatalf("expected 'boom', got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an error to be delivered")
+	}
+}
+
+func TestGoRecover_recoversPanic(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	r.GoRecover(func() error {
+		panic("kaboom")
+	})
+
+	select {
+	case err := <-r.Errors():
+		if !strings.Contains(err.Error(), "kaboom") {
+			t.Fatalf("expected error to mention panic value, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a recovered panic to be delivered as an error")
+	}
+}
+
+func TestGoRecover_failFastStopsRoutines(t *testing.T) {
+	r := NewRoutines(WithFailFast(true))
+	defer r.Wait()
+
+	r.GoRecover(func() error {
+		return errors.New("fatal")
+	})
+
+	select {
+	case <-r.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected WithFailFast to stop the routines after an error")
+	}
+}
+
+func TestDrainErrors_retrievesMoreThanTheChannelBuffer(t *testing.T) {
+	r := NewRoutines()
+	defer r.Stop()
+
+	const n = 12
+	for i := 0; i < n; i++ {
+		r.GoRecover(func() error {
+			return errors.New("boom")
+		})
+	}
+	r.Wait()
+
+	errs := r.DrainErrors()
+	if len(errs) != n {
+		t.Fatalf("expected DrainErrors to return all %d errors, got %d", n, len(errs))
+	}
+	if len(r.DrainErrors()) != 0 {
+		t.Fatal("expected a second DrainErrors call to return nothing")
+	}
+}
+
+func TestGoWithRecover_invokesOnPanic(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	caught := make(chan interface{}, 1)
+	r.GoWithRecover(func() {
+		panic("oops")
+	}, func(recovered interface{}, stack []byte) {
+		caught <- recovered
+	})
+
+	select {
+	case v := <-caught:
+		if v != "oops" {
+			t.Fatalf("expected 'oops', got %v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected onPanic to be invoked")
+	}
+}
@@ -1,6 +1,12 @@
 package routines
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // Routines facilitates concurrency management between a program and its internal
 // long-lived services.
@@ -11,18 +17,73 @@ type Routines interface {
 	// on the child does not affect the parent's routines but calling Stop()
 	// on the parent will also stop all of the children.
 	Child() Routines
+	// Context returns a context.Context whose Done() channel is wired to this Routines'
+	// own Done() channel and whose Err() returns context.Canceled after Stop() has been
+	// called.
+	Context() context.Context
+	// DrainErrors returns every error returned or panicked by goroutines started with
+	// GoRecover() since the last call to DrainErrors(), then clears its internal record of
+	// them.  Unlike Errors(), nothing is dropped regardless of whether anything is reading
+	// concurrently, so callers that only want to inspect errors after Wait() returns
+	// should call DrainErrors() rather than racing to read Errors() before its buffer
+	// fills up.
+	DrainErrors() []error
+	// Errors returns a channel that receives the errors returned or panicked by
+	// goroutines started with GoRecover().
+	Errors() <-chan error
+	// Failures returns a channel that receives the terminal errors of goroutines
+	// started with GoSupervised() once they give up restarting.
+	Failures() <-chan error
 	// Go launches the function as a go routine.
 	Go(func())
+	// GoRecover launches fn as a go routine, recovering any panic and converting it into
+	// an error, and delivers a non-nil returned or recovered error on Errors(), as well as
+	// recording it for DrainErrors().  If the Routines was created with WithFailFast(true),
+	// Stop() is called automatically when such an error arrives.  Errors() is an 8-slot
+	// buffered channel that drops errors beyond the 8th if nothing reads from it
+	// concurrently; call DrainErrors() to see every error instead.
+	GoRecover(fn func() error)
+	// GoSupervised launches fn in a supervised loop, restarting it with a decaying-failure
+	// backoff strategy whenever it panics or returns a non-nil error before Done() closes.
+	// See SupervisorOption for the available tuning knobs.
+	GoSupervised(fn func() error, opts ...SupervisorOption)
+	// GoWithRecover launches fn as a fire-and-forget go routine, recovering any panic and
+	// passing the recovered value and stack trace to onPanic rather than crashing the
+	// program.
+	GoWithRecover(fn func(), onPanic func(recovered interface{}, stack []byte))
+	// Services returns a snapshot of the names of the functions currently running under
+	// GoSupervised().
+	Services() []string
 	// Stop sends a stop signal to all routines started with Go().
 	Stop()
 	// Wait waits for all routines started with Go() to complete before returning.
 	Wait()
+	// WaitContext waits for all routines started with Go() to complete, or for ctx to be
+	// done, whichever happens first.  If ctx finishes first a non-nil error identifying
+	// how many goroutines are still outstanding is returned.
+	WaitContext(ctx context.Context) error
+	// WaitTimeout waits for all routines started with Go() to complete, or for d to
+	// elapse, whichever happens first.  If d elapses first a non-nil error identifying
+	// how many goroutines are still outstanding is returned.
+	WaitTimeout(d time.Duration) error
+}
+
+// RoutinesOption configures a Routines created with NewRoutines().
+type RoutinesOption func(*routines)
+
+// WithFailFast causes a Routines to call Stop() automatically the first time a goroutine
+// started with GoRecover() delivers a non-nil error.
+func WithFailFast(failFast bool) RoutinesOption {
+	return func(r *routines) { r.failFast = failFast }
 }
 
 // NewRoutines creates a routines type.
-func NewRoutines() Routines {
+func NewRoutines(opts ...RoutinesOption) Routines {
 	rv := &routines{
 		doneCh: make(chan struct{})}
+	for _, opt := range opts {
+		opt(rv)
+	}
 	return rv
 }
 
@@ -35,12 +96,69 @@ type routines struct {
 	parent *routines
 	//
 	children sync.WaitGroup
+	//
+	supMut       sync.Mutex
+	supervised   []*supervised
+	failuresCh   chan error
+	failuresOnce sync.Once
+	//
+	errorsCh   chan error
+	errorsOnce sync.Once
+	failFast   bool
+	//
+	errMut   sync.Mutex
+	errQueue []error
+	//
+	// selfCount and childCount mirror waitgroup and children respectively, since a
+	// sync.WaitGroup does not expose its current count; they back the diagnostics
+	// returned by WaitTimeout() and WaitContext() on timeout.
+	selfCount  int32
+	childCount int32
+	//
+	// realWaitgroup, realChildren, realSelfCount, and realChildCount mirror waitgroup,
+	// children, selfCount, and childCount but exclude the bookkeeping goroutine Child()
+	// launches internally to propagate Stop(); that goroutine never exits on its own, so
+	// Service.IsRunning() and Service.Wait() key off these instead, to correctly report a
+	// service whose own Go()/GoRecover()/GoSupervised() work has finished without Stop()
+	// ever being called.
+	realWaitgroup  sync.WaitGroup
+	realChildren   sync.WaitGroup
+	realSelfCount  int32
+	realChildCount int32
+	// realLaunched becomes non-zero the first time real work is launched on this Routines
+	// or one of its immediate children; running() uses it so a Routines that has never
+	// launched any real work is still reported as running, rather than looking like it
+	// already finished.
+	realLaunched int32
+	//
+	// srcCtx is the context.Context this Routines was created from via FromContext, if any;
+	// it is consulted by Context().Value() and propagated to children.
+	srcCtx context.Context
+}
+
+// outstandingErr is returned by WaitTimeout() and WaitContext() when they give up
+// waiting; it reports how many goroutines are still outstanding for diagnostics.
+type outstandingErr struct {
+	self     int32
+	children int32
+}
+
+// Error implements the error interface.
+func (me *outstandingErr) Error() string {
+	return fmt.Sprintf("routines: timed out waiting for %d routine(s) and %d child routine(s) to finish", me.self, me.children)
+}
+
+// Outstanding returns the number of this Routines' own outstanding goroutines and the
+// number of outstanding goroutines belonging to its children, respectively.
+func (me *outstandingErr) Outstanding() (self int32, children int32) {
+	return me.self, me.children
 }
 
 // childrenUp increments the childrenGroup WaitGroup by 1, is go routine safe, and nil pointer safe.
 func (me *routines) childrenUp() {
 	if me != nil {
 		me.children.Add(1)
+		atomic.AddInt32(&me.childCount, 1)
 	}
 }
 
@@ -48,6 +166,26 @@ func (me *routines) childrenUp() {
 func (me *routines) childrenDown() {
 	if me != nil {
 		me.children.Done()
+		atomic.AddInt32(&me.childCount, -1)
+	}
+}
+
+// realChildrenUp is childrenUp's counterpart for real (non-bookkeeping) goroutines; see
+// the comment on realWaitgroup.
+func (me *routines) realChildrenUp() {
+	if me != nil {
+		me.realChildren.Add(1)
+		atomic.AddInt32(&me.realChildCount, 1)
+		atomic.StoreInt32(&me.realLaunched, 1)
+	}
+}
+
+// realChildrenDown is childrenDown's counterpart for real (non-bookkeeping) goroutines;
+// see the comment on realWaitgroup.
+func (me *routines) realChildrenDown() {
+	if me != nil {
+		me.realChildren.Done()
+		atomic.AddInt32(&me.realChildCount, -1)
 	}
 }
 
@@ -69,6 +207,7 @@ func (me *routines) Child() Routines {
 	}
 	rv := NewRoutines()
 	rv.(*routines).parent = me
+	rv.(*routines).srcCtx = me.srcCtx
 	//
 	// Ensure the child is properly closed when the parent is stopped but also that the
 	// child can stop early.
@@ -84,7 +223,7 @@ func (me *routines) Child() Routines {
 		}
 	done:
 	}
-	rv.Go(fn)
+	rv.(*routines).goInternal(fn)
 	//
 	return rv
 }
@@ -95,10 +234,37 @@ func (me *routines) Go(fn func()) {
 	if me != nil {
 		// Increment our own WaitGroup.
 		me.waitgroup.Add(1)
+		atomic.AddInt32(&me.selfCount, 1)
+		me.realWaitgroup.Add(1)
+		atomic.AddInt32(&me.realSelfCount, 1)
+		atomic.StoreInt32(&me.realLaunched, 1)
 		// Our parent contains a WaitGroup for all go routines launched by its children; increment that WaitGroup also.
 		me.parent.childrenUp()
+		me.parent.realChildrenUp()
 		go func() {
 			defer me.waitgroup.Done()
+			defer atomic.AddInt32(&me.selfCount, -1)
+			defer me.realWaitgroup.Done()
+			defer atomic.AddInt32(&me.realSelfCount, -1)
+			defer me.parent.childrenDown()
+			defer me.parent.realChildrenDown()
+			fn()
+		}()
+	}
+}
+
+// goInternal launches fn as a go routine the same way Go() does, except it is not counted
+// as "real" outstanding work; it exists for Routines' own bookkeeping goroutines, such as
+// the one Child() starts to propagate Stop(), which by design never exit on their own and
+// so must not keep Service.IsRunning()/Wait() reporting a service as running forever.
+func (me *routines) goInternal(fn func()) {
+	if me != nil {
+		me.waitgroup.Add(1)
+		atomic.AddInt32(&me.selfCount, 1)
+		me.parent.childrenUp()
+		go func() {
+			defer me.waitgroup.Done()
+			defer atomic.AddInt32(&me.selfCount, -1)
 			defer me.parent.childrenDown()
 			fn()
 		}()
@@ -124,3 +290,79 @@ func (me *routines) Wait() {
 		me.waitgroup.Wait()
 	}
 }
+
+// waitDone returns a channel that closes once both me.children and me.waitgroup have
+// finished, so the caller can select against it alongside a timeout or ctx.Done().
+func (me *routines) waitDone() <-chan struct{} {
+	doneCh := make(chan struct{})
+	go func() {
+		me.children.Wait()
+		me.waitgroup.Wait()
+		close(doneCh)
+	}()
+	return doneCh
+}
+
+// outstandingError describes how many goroutines are still outstanding; it is returned
+// by WaitTimeout() and WaitContext() when they give up waiting.
+func (me *routines) outstandingError() error {
+	return &outstandingErr{
+		self:     atomic.LoadInt32(&me.selfCount),
+		children: atomic.LoadInt32(&me.childCount),
+	}
+}
+
+// running reports, without blocking, whether this Routines or any of its children still
+// has an outstanding real goroutine launched by Go() (directly, or indirectly through
+// GoRecover(), GoWithRecover(), or GoSupervised()).  Unlike selfCount/childCount, this
+// ignores the internal bookkeeping goroutine Child() starts to propagate Stop(), which
+// never exits on its own; Service.IsRunning() relies on this to correctly report a
+// service whose own work finished without Stop() ever being called.  A Routines that has
+// never launched any real work is reported as running, since there is nothing yet for it
+// to have finished.
+func (me *routines) running() bool {
+	if atomic.LoadInt32(&me.realLaunched) == 0 {
+		return true
+	}
+	return atomic.LoadInt32(&me.realSelfCount) > 0 || atomic.LoadInt32(&me.realChildCount) > 0
+}
+
+// realWait waits for all real goroutines launched by Go() (directly, or indirectly
+// through GoRecover(), GoWithRecover(), or GoSupervised()) to complete, ignoring the
+// internal bookkeeping goroutine Child() starts to propagate Stop().  Service.Wait()
+// relies on this so it returns once a service's own work finishes, even if nobody ever
+// calls Stop().
+func (me *routines) realWait() {
+	me.realChildren.Wait()
+	me.realWaitgroup.Wait()
+}
+
+// WaitTimeout waits for all routines started with Go() to complete, or for d to elapse,
+// whichever happens first.
+func (me *routines) WaitTimeout(d time.Duration) error {
+	if me == nil {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-me.waitDone():
+		return nil
+	case <-timer.C:
+		return me.outstandingError()
+	}
+}
+
+// WaitContext waits for all routines started with Go() to complete, or for ctx to be
+// done, whichever happens first.
+func (me *routines) WaitContext(ctx context.Context) error {
+	if me == nil {
+		return nil
+	}
+	select {
+	case <-me.waitDone():
+		return nil
+	case <-ctx.Done():
+		return me.outstandingError()
+	}
+}
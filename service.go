@@ -1,16 +1,77 @@
 package routines
 
 import (
-	"github.com/nofeaturesonlybugs/errors"
+	"context"
 	"sync"
+	"time"
+
+	"github.com/nofeaturesonlybugs/errors"
 )
 
 // Service is the interface for a long-lived process that can start once and later be stopped.
 type Service interface {
 	// Start starts the service.
 	Start(Routines) error
+	// StartContext starts the service using a Routines created from ctx via FromContext,
+	// so that services built around context.Context can compose with Routines-managed
+	// code without either side losing ordered shutdown.
+	StartContext(ctx context.Context) error
 	// Stop stops the service.
 	Stop()
+	// StopTimeout stops the service but abandons waiting for it to fully stop once d
+	// elapses, returning a non-nil error identifying how many goroutines are still
+	// outstanding so a buggy service can be diagnosed instead of hanging the caller
+	// forever.  If the deadline is not reached the service is left fully stopped.
+	StopTimeout(d time.Duration) error
+	// IsRunning reports whether the service is currently started and has not yet
+	// stopped.
+	IsRunning() bool
+	// Name returns the service's diagnostic name, set via WithServiceName(); the zero
+	// value is "unnamed".
+	Name() string
+	// Wait blocks until the service's goroutines have finished, whether after Stop() or
+	// on their own; it returns immediately if the service has never been started or has
+	// launched no goroutines.
+	Wait()
+}
+
+// ServiceEvents groups the optional lifecycle callbacks a Service can report through:
+// OnStart fires after a successful Start(), OnStop fires after Stop() has finished
+// draining the service's goroutines, and OnError fires when Start() returns a non-nil
+// error.
+type ServiceEvents struct {
+	OnStart func()
+	OnStop  func()
+	OnError func(error)
+}
+
+// ServiceOption configures a Service created with NewService().
+type ServiceOption func(*service)
+
+// WithServiceName sets the diagnostic name returned by Service.Name().
+func WithServiceName(name string) ServiceOption {
+	return func(s *service) { s.name = name }
+}
+
+// WithOnStart registers a callback invoked after the service has started successfully.
+func WithOnStart(fn func()) ServiceOption {
+	return func(s *service) { s.events.OnStart = fn }
+}
+
+// WithOnStop registers a callback invoked after the service has fully stopped.
+func WithOnStop(fn func()) ServiceOption {
+	return func(s *service) { s.events.OnStop = fn }
+}
+
+// WithOnError registers a callback invoked when Start() returns a non-nil error.
+func WithOnError(fn func(error)) ServiceOption {
+	return func(s *service) { s.events.OnError = fn }
+}
+
+// WithEvents registers a pre-built ServiceEvents, overwriting any callbacks already set
+// by WithOnStart, WithOnStop, or WithOnError.
+func WithEvents(events ServiceEvents) ServiceOption {
+	return func(s *service) { s.events = events }
 }
 
 // service is the hidden internal type that implements the service interface.
@@ -18,17 +79,24 @@ type service struct {
 	start    func(Routines) error
 	mut      sync.Mutex
 	routines Routines
+	//
+	name   string
+	events ServiceEvents
 }
 
 // NewService creates a new service that will launch the start method only once until it
 // is stopped.  If start is nil then a stub function will be created that returns an error.
-func NewService(start func(Routines) error) Service {
+func NewService(start func(Routines) error, opts ...ServiceOption) Service {
 	if start == nil {
 		start = func(rtns Routines) error {
 			return errors.NilArgument("start").Type(start)
 		}
 	}
-	return &service{start: start}
+	rv := &service{start: start}
+	for _, opt := range opts {
+		opt(rv)
+	}
+	return rv
 }
 
 // Start starts the service.
@@ -54,11 +122,26 @@ func (me *service) Start(routines Routines) error {
 	err = me.start(child)
 	if err == nil {
 		me.routines = child
+		if me.events.OnStart != nil {
+			me.events.OnStart()
+		}
+	} else if me.events.OnError != nil {
+		me.events.OnError(err)
 	}
 
 	return err
 }
 
+// StartContext starts the service using a Routines created from ctx via FromContext, so
+// that services built around context.Context can compose with Routines-managed code
+// without either side losing ordered shutdown.
+func (me *service) StartContext(ctx context.Context) error {
+	if me == nil {
+		return errors.NilReceiver().Type(me)
+	}
+	return me.Start(FromContext(ctx))
+}
+
 // Stop stops the service.
 func (me *service) Stop() {
 	if me != nil {
@@ -68,6 +151,92 @@ func (me *service) Stop() {
 			me.routines.Stop()
 			me.routines.Wait()
 			me.routines = nil
+			if me.events.OnStop != nil {
+				me.events.OnStop()
+			}
 		}
 	}
 }
+
+// StopTimeout stops the service but abandons waiting for it to fully stop once d
+// elapses, returning a non-nil error identifying how many goroutines are still
+// outstanding so a buggy service can be diagnosed instead of hanging the caller forever.
+// If the deadline is not reached the service is left fully stopped.
+func (me *service) StopTimeout(d time.Duration) error {
+	if me == nil {
+		return errors.NilReceiver().Type(me)
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	if me.routines == nil {
+		return nil
+	}
+	me.routines.Stop()
+	if err := me.routines.WaitTimeout(d); err != nil {
+		return err
+	}
+	me.routines = nil
+	if me.events.OnStop != nil {
+		me.events.OnStop()
+	}
+	return nil
+}
+
+// IsRunning reports whether the service is currently started and has not yet stopped.
+// A service whose goroutines have all finished on their own - for example a plain Go(),
+// GoRecover(), or GoSupervised() function that returned without anyone calling Stop() -
+// is reported as not running even though Stop() was never called.
+func (me *service) IsRunning() bool {
+	if me == nil {
+		return false
+	}
+	me.mut.Lock()
+	defer me.mut.Unlock()
+	if me.routines == nil {
+		return false
+	}
+	if r, ok := me.routines.(*routines); ok {
+		return r.running()
+	}
+	// Fall back to Done() for any other Routines implementation; this can't tell the
+	// difference between "stopped" and "finished on its own without Stop()".
+	select {
+	case <-me.routines.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// Name returns the service's diagnostic name, set via WithServiceName(); the zero value
+// is "unnamed".
+func (me *service) Name() string {
+	if me == nil || me.name == "" {
+		return "unnamed"
+	}
+	return me.name
+}
+
+// Wait blocks until the service's goroutines have fully finished, whether because
+// someone called Stop() or because they returned on their own - as a plain Go(),
+// GoRecover(), or GoSupervised() function can - without Stop() ever being called.  It
+// returns immediately if the service has never been started, or if it has launched no
+// goroutines, in which case IsRunning() still reports true.
+func (me *service) Wait() {
+	if me == nil {
+		return
+	}
+	me.mut.Lock()
+	rtns := me.routines
+	me.mut.Unlock()
+	if rtns == nil {
+		return
+	}
+	if r, ok := rtns.(*routines); ok {
+		r.realWait()
+		return
+	}
+	// Fall back to Wait() for any other Routines implementation; this can't tell the
+	// difference between "stopped" and "finished on its own without Stop()".
+	rtns.Wait()
+}
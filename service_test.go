@@ -0,0 +1,110 @@
+package routines
+
+import (
+	"testing"
+	"time"
+)
+
+func TestService_NameDefault(t *testing.T) {
+	svc := NewService(func(Routines) error { return nil })
+	if svc.Name() != "unnamed" {
+		t.Fatalf("expected 'unnamed', got %q", svc.Name())
+	}
+}
+
+func TestService_NameAndLifecycleEvents(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+
+	started, stopped := false, false
+	svc := NewService(
+		func(Routines) error { return nil },
+		WithServiceName("worker"),
+		WithOnStart(func() { started = true }),
+		WithOnStop(func() { stopped = true }),
+	)
+
+	if svc.Name() != "worker" {
+		t.Fatalf("expected 'worker', got %q", svc.Name())
+	}
+	if svc.IsRunning() {
+		t.Fatal("expected service to not be running before Start()")
+	}
+
+	if err := svc.Start(r); err != nil {
+		t.Fatalf("unexpected error starting service: %v", err)
+	}
+	if !started {
+		t.Fatal("expected OnStart to be invoked")
+	}
+	if !svc.IsRunning() {
+		t.Fatal("expected service to be running after Start()")
+	}
+
+	svc.Stop()
+	if !stopped {
+		t.Fatal("expected OnStop to be invoked")
+	}
+	if svc.IsRunning() {
+		t.Fatal("expected service to not be running after Stop()")
+	}
+	svc.Wait()
+}
+
+func TestService_OnErrorCallback(t *testing.T) {
+	var got error
+	svc := NewService(nil, WithOnError(func(err error) { got = err }))
+
+	r := NewRoutines()
+	defer r.Wait()
+
+	if err := svc.Start(r); err == nil {
+		t.Fatal("expected an error starting a nil-start service")
+	}
+	if got == nil {
+		t.Fatal("expected OnError to be invoked with a non-nil error")
+	}
+}
+
+func TestService_Wait_neverStarted(t *testing.T) {
+	svc := NewService(func(Routines) error { return nil })
+	svc.Wait()
+}
+
+func TestService_IsRunningAndWait_finishWithoutStop(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	done := make(chan struct{})
+	svc := NewService(func(child Routines) error {
+		child.Go(func() { close(done) })
+		return nil
+	})
+
+	if err := svc.Start(r); err != nil {
+		t.Fatalf("unexpected error starting service: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("service's goroutine never ran")
+	}
+
+	waitDone := make(chan struct{})
+	go func() {
+		svc.Wait()
+		close(waitDone)
+	}()
+
+	select {
+	case <-waitDone:
+	case <-time.After(time.Second):
+		t.Fatal("expected Wait() to return once the service's own goroutine finished, even though Stop() was never called")
+	}
+
+	if svc.IsRunning() {
+		t.Fatal("expected IsRunning() to report false once the service's own goroutine finished without Stop() being called")
+	}
+}
@@ -0,0 +1,254 @@
+package routines
+
+import (
+	"fmt"
+	"math"
+	"runtime/debug"
+	"time"
+)
+
+// SupervisorOption configures the restart behavior of a goroutine launched with
+// GoSupervised().
+type SupervisorOption func(*supervisorConfig)
+
+// supervisorConfig holds the tunable parameters for a single GoSupervised goroutine.
+type supervisorConfig struct {
+	name               string
+	failureThreshold   float64
+	halfLife           time.Duration
+	minRestartInterval time.Duration
+	maxBackoff         time.Duration
+	stopOnGiveUp       bool
+	onPanic            func(recovered interface{}, stack []byte)
+}
+
+// newSupervisorConfig creates a supervisorConfig populated with sane defaults.
+func newSupervisorConfig() *supervisorConfig {
+	return &supervisorConfig{
+		name:               "unnamed",
+		failureThreshold:   5,
+		halfLife:           10 * time.Second,
+		minRestartInterval: 100 * time.Millisecond,
+		maxBackoff:         30 * time.Second,
+	}
+}
+
+// WithName sets the diagnostic name reported by Services() and included in terminal
+// errors delivered on Failures().
+func WithName(name string) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.name = name }
+}
+
+// WithFailureThreshold sets the decayed failure count that, once exceeded, causes the
+// supervisor to escalate the restart delay; it gives up restarting fn only once that
+// delay reaches MaxBackoff.
+func WithFailureThreshold(threshold float64) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.failureThreshold = threshold }
+}
+
+// WithHalfLife sets the window over which the decayed failure count halves; it is the T
+// in "N failures in T seconds triggers backoff."
+func WithHalfLife(d time.Duration) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.halfLife = d }
+}
+
+// WithMinRestartInterval sets the minimum delay observed between restarts even while the
+// failure count remains under the configured threshold.
+func WithMinRestartInterval(d time.Duration) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.minRestartInterval = d }
+}
+
+// WithMaxBackoff caps the escalating delay calculated once the failure threshold has
+// been exceeded.  Once the calculated delay reaches MaxBackoff the supervisor gives up
+// restarting fn rather than retrying at an unchanging interval forever.
+func WithMaxBackoff(d time.Duration) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.maxBackoff = d }
+}
+
+// WithStopOnGiveUp causes the supervisor to call Stop() on the Routines that launched it
+// once it gives up restarting fn, in addition to delivering the terminal error on
+// Failures().
+func WithStopOnGiveUp(stop bool) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.stopOnGiveUp = stop }
+}
+
+// WithOnPanic registers a callback invoked whenever fn panics; it receives the recovered
+// value and the stack trace captured by debug.Stack().
+func WithOnPanic(fn func(recovered interface{}, stack []byte)) SupervisorOption {
+	return func(cfg *supervisorConfig) { cfg.onPanic = fn }
+}
+
+// supervised tracks the restart bookkeeping for a single GoSupervised function.
+type supervised struct {
+	name         string
+	lastFailure  time.Time
+	failureCount float64
+}
+
+// decay applies exponential decay to the failure count based on the time elapsed since
+// the previous failure, records a new failure at now, and returns the updated count.
+func (me *supervised) decay(now time.Time, halfLife time.Duration) float64 {
+	if !me.lastFailure.IsZero() && halfLife > 0 {
+		elapsed := now.Sub(me.lastFailure)
+		me.failureCount = me.failureCount * math.Pow(0.5, float64(elapsed)/float64(halfLife))
+	}
+	me.failureCount++
+	me.lastFailure = now
+	return me.failureCount
+}
+
+// backoff calculates the delay to observe before the next restart attempt once the
+// decayed failure count has exceeded FailureThreshold.  The delay doubles per failure
+// above the threshold and is capped at MaxBackoff; once the returned delay reaches
+// MaxBackoff the caller should treat the supervisor as exhausted and give up rather than
+// keep retrying at an unchanging interval.  All arithmetic is done in float64 and clamped
+// before converting to a time.Duration so a very large failure count cannot overflow it.
+func backoff(cfg *supervisorConfig, count float64) time.Duration {
+	over := count - cfg.failureThreshold
+	minF, maxF := float64(cfg.minRestartInterval), float64(cfg.maxBackoff)
+	d := minF * math.Pow(2, over)
+	if d > maxF || math.IsInf(d, 1) {
+		d = maxF
+	}
+	if d < minF {
+		d = minF
+	}
+	return time.Duration(d)
+}
+
+// addSupervised registers sv as a currently running supervised function so it shows up
+// in Services().
+func (me *routines) addSupervised(sv *supervised) {
+	if me == nil {
+		return
+	}
+	me.supMut.Lock()
+	defer me.supMut.Unlock()
+	me.supervised = append(me.supervised, sv)
+}
+
+// removeSupervised unregisters sv once its GoSupervised loop has ended.
+func (me *routines) removeSupervised(sv *supervised) {
+	if me == nil {
+		return
+	}
+	me.supMut.Lock()
+	defer me.supMut.Unlock()
+	for k, v := range me.supervised {
+		if v == sv {
+			me.supervised = append(me.supervised[:k], me.supervised[k+1:]...)
+			return
+		}
+	}
+}
+
+// Services returns a snapshot of the names of the functions currently running under
+// GoSupervised().
+func (me *routines) Services() []string {
+	if me == nil {
+		return nil
+	}
+	me.supMut.Lock()
+	defer me.supMut.Unlock()
+	rv := make([]string, 0, len(me.supervised))
+	for _, sv := range me.supervised {
+		rv = append(rv, sv.name)
+	}
+	return rv
+}
+
+// initFailures lazily creates the buffered channel backing Failures().
+func (me *routines) initFailures() {
+	me.failuresOnce.Do(func() {
+		me.failuresCh = make(chan error, 8)
+	})
+}
+
+// Failures returns a channel that receives the terminal errors of goroutines started
+// with GoSupervised() once they give up restarting.  The channel is buffered; a failure
+// is dropped rather than blocking the supervisor if the buffer is full and nobody is
+// reading from it.
+func (me *routines) Failures() <-chan error {
+	if me == nil {
+		return nil
+	}
+	me.initFailures()
+	return me.failuresCh
+}
+
+// sendFailure delivers err on Failures() without blocking the supervisor.
+func (me *routines) sendFailure(err error) {
+	me.initFailures()
+	select {
+	case me.failuresCh <- err:
+	default:
+	}
+}
+
+// runSupervised invokes fn once, recovering any panic and converting it into an error
+// annotated with the stack trace captured by debug.Stack().
+func runSupervised(fn func() error, cfg *supervisorConfig) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			if cfg.onPanic != nil {
+				cfg.onPanic(r, stack)
+			}
+			err = fmt.Errorf("panic in supervised routine %q: %v\n%s", cfg.name, r, stack)
+		}
+	}()
+	return fn()
+}
+
+// GoSupervised launches fn in a supervised loop modeled on Erlang-style supervision
+// trees.  Each time fn panics or returns a non-nil error before Done() closes, the
+// supervisor accumulates a decayed failure count - each failure adds 1 and the count
+// decays by half every HalfLife.  While the count stays at or under FailureThreshold, fn
+// is restarted after a flat MinRestartInterval delay.  Once the count exceeds
+// FailureThreshold the retry delay escalates - doubling per failure above the threshold,
+// capped at MaxBackoff - and once that delay reaches MaxBackoff the supervisor gives up:
+// the terminal error is delivered on Failures() and, if WithStopOnGiveUp(true) was
+// supplied, Stop() is called.  A fn that returns nil, or whose restart is interrupted by
+// Done() closing, ends the supervised loop without restarting.
+func (me *routines) GoSupervised(fn func() error, opts ...SupervisorOption) {
+	if me == nil {
+		return
+	}
+	cfg := newSupervisorConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	sv := &supervised{name: cfg.name}
+	me.addSupervised(sv)
+	me.Go(func() {
+		defer me.removeSupervised(sv)
+		for {
+			err := runSupervised(fn, cfg)
+			if err == nil {
+				return
+			}
+			select {
+			case <-me.Done():
+				return
+			default:
+			}
+			count := sv.decay(time.Now(), cfg.halfLife)
+			delay := cfg.minRestartInterval
+			if count > cfg.failureThreshold {
+				delay = backoff(cfg, count)
+				if delay >= cfg.maxBackoff {
+					me.sendFailure(fmt.Errorf("supervised routine %q given up after %.2f decayed failures: %w", cfg.name, count, err))
+					if cfg.stopOnGiveUp {
+						me.Stop()
+					}
+					return
+				}
+			}
+			select {
+			case <-me.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	})
+}
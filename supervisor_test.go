@@ -0,0 +1,94 @@
+package routines
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSupervised_decay(t *testing.T) {
+	sv := &supervised{}
+	now := time.Now()
+
+	count := sv.decay(now, time.Second)
+	if count != 1 {
+		t.Fatalf("expected count of 1, got %v", count)
+	}
+
+	// A failure a full half-life later should decay the first failure by half before adding 1.
+	count = sv.decay(now.Add(time.Second), time.Second)
+	if count < 1.4 || count > 1.6 {
+		t.Fatalf("expected count near 1.5, got %v", count)
+	}
+}
+
+func TestBackoff_escalatesThenCapsAtMaxBackoff(t *testing.T) {
+	cfg := &supervisorConfig{
+		failureThreshold:   2,
+		minRestartInterval: time.Millisecond,
+		maxBackoff:         16 * time.Millisecond,
+	}
+
+	// At or under the threshold backoff() is not consulted by GoSupervised, but it
+	// should still report the flat minimum if asked.
+	if d := backoff(cfg, 2); d != cfg.minRestartInterval {
+		t.Fatalf("expected flat minimum at the threshold, got %v", d)
+	}
+
+	// One failure above the threshold should double the delay versus the threshold.
+	d1 := backoff(cfg, 3)
+	if d1 <= cfg.minRestartInterval {
+		t.Fatalf("expected an escalated delay above the minimum, got %v", d1)
+	}
+
+	// Further failures should keep escalating until the delay saturates at MaxBackoff.
+	d2 := backoff(cfg, 4)
+	if d2 <= d1 {
+		t.Fatalf("expected delay to keep escalating, got %v then %v", d1, d2)
+	}
+	if d := backoff(cfg, 10); d != cfg.maxBackoff {
+		t.Fatalf("expected the delay to saturate at MaxBackoff, got %v", d)
+	}
+}
+
+func TestGoSupervised_restartsUntilSuccess(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	var attempts int32
+	done := make(chan struct{})
+	r.GoSupervised(func() error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("not yet")
+		}
+		close(done)
+		return nil
+	}, WithMinRestartInterval(time.Millisecond))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("supervised routine never succeeded")
+	}
+}
+
+func TestGoSupervised_givesUpAfterThreshold(t *testing.T) {
+	r := NewRoutines()
+	defer r.Wait()
+	defer r.Stop()
+
+	r.GoSupervised(func() error {
+		return errors.New("always fails")
+	}, WithName("flaky"), WithFailureThreshold(1), WithMinRestartInterval(time.Millisecond), WithMaxBackoff(time.Millisecond))
+
+	select {
+	case err := <-r.Failures():
+		if err == nil {
+			t.Fatal("expected a non-nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a failure to be delivered")
+	}
+}
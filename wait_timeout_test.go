@@ -0,0 +1,48 @@
+package routines
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRoutines_WaitTimeout_expires(t *testing.T) {
+	r := NewRoutines()
+	defer r.Stop()
+	defer r.Wait()
+
+	block := make(chan struct{})
+	defer close(block)
+	r.Go(func() { <-block })
+
+	err := r.WaitTimeout(10 * time.Millisecond)
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}
+
+func TestRoutines_WaitTimeout_succeeds(t *testing.T) {
+	r := NewRoutines()
+	r.Go(func() {})
+
+	if err := r.WaitTimeout(time.Second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestRoutines_WaitContext_cancelled(t *testing.T) {
+	r := NewRoutines()
+	defer r.Stop()
+	defer r.Wait()
+
+	block := make(chan struct{})
+	defer close(block)
+	r.Go(func() { <-block })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := r.WaitContext(ctx); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}